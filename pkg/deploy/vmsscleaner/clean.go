@@ -0,0 +1,187 @@
+package vmsscleaner
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/Azure/ARO-RP/pkg/util/azureclient/mgmt/compute"
+	"github.com/Azure/ARO-RP/pkg/util/azureclient/mgmt/network"
+)
+
+// gatewayVMSSPrefix is the naming prefix applied to every gateway VMSS
+// deployed by the RP, across all OS offerings.
+const gatewayVMSSPrefix = "gateway-vmss-"
+
+// Cleaner removes VMSS deployments left behind by a failed rolling
+// deployment and keeps the health probes on the serving gateway VMSS
+// up to date.
+type Cleaner interface {
+	// ConditionsGetter lets callers such as the RP admin API surface cleanup
+	// progress without needing their own reference to the concrete cleaner.
+	ConditionsGetter
+
+	RemoveFailedNewScaleset(ctx context.Context, resourceGroupName, vmssToDelete string) *RetryError
+	UpdateVMSSProbes(ctx context.Context, resourceGroupName string) *RetryError
+	RollingUpgradeScaleset(ctx context.Context, resourceGroupName, vmssName string, opts RollingUpgradeOptions) error
+	ReimageInstances(ctx context.Context, resourceGroupName, vmssName string, instanceIDs []string) error
+	CleanOrphanedResources(ctx context.Context, resourceGroupName, vmssNamePrefix string, dryRun bool) *RetryError
+}
+
+type cleaner struct {
+	log *logrus.Entry
+
+	vmss              compute.VirtualMachineScaleSetsClient
+	vmssvm            compute.VirtualMachineScaleSetVMsClient
+	disks             compute.DisksClient
+	interfaces        network.InterfacesClient
+	publicIPAddresses network.PublicIPAddressesClient
+
+	conditionsMu sync.Mutex
+	conditions   []Condition
+}
+
+// NewCleaner returns a new Cleaner.
+func NewCleaner(
+	log *logrus.Entry,
+	vmss compute.VirtualMachineScaleSetsClient,
+	vmssvm compute.VirtualMachineScaleSetVMsClient,
+	disks compute.DisksClient,
+	interfaces network.InterfacesClient,
+	publicIPAddresses network.PublicIPAddressesClient,
+) Cleaner {
+	return &cleaner{
+		log: log,
+
+		vmss:              vmss,
+		vmssvm:            vmssvm,
+		disks:             disks,
+		interfaces:        interfaces,
+		publicIPAddresses: publicIPAddresses,
+	}
+}
+
+// RemoveFailedNewScaleset deletes vmssToDelete, the new scaleset of a failed
+// rolling deployment, once it shows up in rg alongside other scalesets; it is
+// never deleted while it is the only scaleset in rg. It returns nil once no
+// further action is possible or required, and a *RetryError describing
+// whether and when the caller should retry otherwise.
+func (c *cleaner) RemoveFailedNewScaleset(ctx context.Context, resourceGroupName, vmssToDelete string) *RetryError {
+	scalesets, err := c.vmss.List(ctx, resourceGroupName)
+	if err != nil {
+		c.log.Error(err)
+		return classifyError(err)
+	}
+
+	if len(scalesets) == 0 {
+		c.log.Printf("no scalesets found in %s, will retry", resourceGroupName)
+		c.setCondition(ConditionScaleSetDesiredReplicas, ConditionUnknown, ReasonScaleSetScalingUp, "waiting for new scaleset to appear")
+		return retriable()
+	}
+
+	if len(scalesets) == 1 && scalesets[0].Name != nil && strings.EqualFold(*scalesets[0].Name, vmssToDelete) {
+		c.log.Printf("scaleset %s is the only scaleset in %s, not deleting it", *scalesets[0].Name, resourceGroupName)
+		return nil
+	}
+
+	var foundName string
+	for _, vmss := range scalesets {
+		if vmss.Name != nil && strings.EqualFold(*vmss.Name, vmssToDelete) {
+			foundName = *vmss.Name
+			break
+		}
+	}
+
+	if foundName == "" {
+		c.log.Printf("scaleset %s not found, will retry", vmssToDelete)
+		c.setCondition(ConditionScaleSetDesiredReplicas, ConditionUnknown, ReasonScaleSetScalingUp, "waiting for new scaleset to appear")
+		return retriable()
+	}
+
+	c.setCondition(ConditionScaleSetDesiredReplicas, ConditionFalse, ReasonScaleSetScalingDown, "failed scaleset still present, draining ahead of deletion")
+	c.setCondition(ConditionScaleSetDeleting, ConditionTrue, ReasonScaleSetProvisionFailed, "deleting scaleset "+foundName)
+
+	c.log.Printf("deleting scaleset %s", foundName)
+	err = c.vmss.DeleteAndWait(ctx, resourceGroupName, foundName)
+	if err != nil {
+		c.log.Error(err)
+		return classifyError(err)
+	}
+
+	c.setCondition(ConditionScaleSetDeleting, ConditionFalse, "", "scaleset "+foundName+" removed")
+
+	return retriable()
+}
+
+// UpdateVMSSProbes strips the unhealthy health probe reference from every
+// gateway VMSS in rg so that instances aren't taken out of rotation by a
+// probe that no longer exists. It returns nil on success or when there was
+// nothing to do, and a *RetryError if a scaleset update failed.
+func (c *cleaner) UpdateVMSSProbes(ctx context.Context, resourceGroupName string) *RetryError {
+	scalesets, err := c.vmss.List(ctx, resourceGroupName)
+	if err != nil {
+		c.log.Error(err)
+		return classifyError(err)
+	}
+
+	for _, vmss := range scalesets {
+		if vmss.Name == nil || !strings.HasPrefix(strings.ToLower(*vmss.Name), gatewayVMSSPrefix) {
+			continue
+		}
+
+		if vmss.VirtualMachineScaleSetProperties != nil &&
+			vmss.VirtualMachineProfile != nil &&
+			vmss.VirtualMachineProfile.NetworkProfile != nil {
+			vmss.VirtualMachineProfile.NetworkProfile.HealthProbe = nil
+		}
+
+		c.log.Printf("updating probes on scaleset %s", *vmss.Name)
+		err = c.vmss.CreateOrUpdateAndWait(ctx, resourceGroupName, *vmss.Name, vmss)
+		if err != nil {
+			c.log.Error(err)
+			return classifyError(err)
+		}
+
+		if err := c.refreshModelUpdatedCondition(ctx, resourceGroupName, *vmss.Name); err != nil {
+			c.log.Error(err)
+			return classifyError(err)
+		}
+	}
+
+	return nil
+}
+
+// refreshModelUpdatedCondition sets ConditionScaleSetModelUpdated by
+// comparing each instance's LatestModelApplied flag, i.e. whether it has
+// actually picked up vmssName's current model, rather than assuming a
+// successful update call means the rollout has finished everywhere.
+func (c *cleaner) refreshModelUpdatedCondition(ctx context.Context, resourceGroupName, vmssName string) error {
+	instances, err := c.vmssvm.List(ctx, resourceGroupName, vmssName, "", "", "")
+	if err != nil {
+		return err
+	}
+
+	var outOfDate int
+	for _, instance := range instances {
+		if instance.VirtualMachineScaleSetVMProperties == nil ||
+			instance.VirtualMachineScaleSetVMProperties.LatestModelApplied == nil ||
+			!*instance.VirtualMachineScaleSetVMProperties.LatestModelApplied {
+			outOfDate++
+		}
+	}
+
+	if outOfDate > 0 {
+		c.setCondition(ConditionScaleSetModelUpdated, ConditionFalse, ReasonScaleSetModelOutOfDate,
+			fmt.Sprintf("%d instance(s) of %s have not yet picked up the current model", outOfDate, vmssName))
+		return nil
+	}
+
+	c.setCondition(ConditionScaleSetModelUpdated, ConditionTrue, "", vmssName+" is running the desired model")
+	return nil
+}
@@ -7,6 +7,7 @@ import (
 	"context"
 	"errors"
 	"io"
+	"strings"
 	"testing"
 
 	mgmtcompute "github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-01/compute"
@@ -109,6 +110,20 @@ func TestRemoveFailedScaleset(t *testing.T) {
 			},
 			want: true,
 		},
+		{
+			name: "Target scaleset found with different case, attempt deletion using ARM-returned case, deletion succeeded, retry",
+			mocks: func(vmss *mock_compute.MockVirtualMachineScaleSetsClient) {
+				vmss.EXPECT().List(ctx, rg).Return(
+					[]mgmtcompute.VirtualMachineScaleSet{
+						{Name: to.StringPtr(servingVMSS)},
+						{Name: to.StringPtr(strings.ToLower(vmssToDelete))},
+					},
+					nil,
+				)
+				vmss.EXPECT().DeleteAndWait(ctx, rg, strings.ToLower(vmssToDelete)).Return(nil)
+			},
+			want: true,
+		},
 	} {
 		t.Run(tt.name, func(t *testing.T) {
 			controller := gomock.NewController(t)
@@ -123,7 +138,7 @@ func TestRemoveFailedScaleset(t *testing.T) {
 			}
 
 			retry := c.RemoveFailedNewScaleset(ctx, rg, vmssToDelete)
-			if retry != tt.want {
+			if (retry != nil && retry.Retriable) != tt.want {
 				t.Error(retry)
 			}
 		})
@@ -137,6 +152,8 @@ func TestUpdateProbe(t *testing.T) {
 		listErr           error
 		createOrUpdateErr error
 		listReturn        []mgmtcompute.VirtualMachineScaleSet
+		instances         []mgmtcompute.VirtualMachineScaleSetVM
+		wantCondition     ConditionStatus
 	}{
 		{
 			name:     "list error",
@@ -159,7 +176,7 @@ func TestUpdateProbe(t *testing.T) {
 			},
 		},
 		{
-			name:     "success",
+			name:     "success, every instance has picked up the new model",
 			expected: true,
 			listReturn: []mgmtcompute.VirtualMachineScaleSet{
 				{
@@ -171,6 +188,28 @@ func TestUpdateProbe(t *testing.T) {
 					},
 				},
 			},
+			instances: []mgmtcompute.VirtualMachineScaleSetVM{
+				{VirtualMachineScaleSetVMProperties: &mgmtcompute.VirtualMachineScaleSetVMProperties{LatestModelApplied: to.BoolPtr(true)}},
+			},
+			wantCondition: ConditionTrue,
+		},
+		{
+			name:     "success, but an instance hasn't picked up the new model yet",
+			expected: true,
+			listReturn: []mgmtcompute.VirtualMachineScaleSet{
+				{
+					Name: to.StringPtr("gateway-vmss-redhat"),
+					VirtualMachineScaleSetProperties: &mgmtcompute.VirtualMachineScaleSetProperties{
+						VirtualMachineProfile: &mgmtcompute.VirtualMachineScaleSetVMProfile{
+							NetworkProfile: &mgmtcompute.VirtualMachineScaleSetNetworkProfile{},
+						},
+					},
+				},
+			},
+			instances: []mgmtcompute.VirtualMachineScaleSetVM{
+				{VirtualMachineScaleSetVMProperties: &mgmtcompute.VirtualMachineScaleSetVMProperties{LatestModelApplied: to.BoolPtr(false)}},
+			},
+			wantCondition: ConditionFalse,
 		},
 		{
 			name:     "not gateway",
@@ -181,6 +220,24 @@ func TestUpdateProbe(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:     "gateway with mixed-case prefix from ARM still matched",
+			expected: true,
+			listReturn: []mgmtcompute.VirtualMachineScaleSet{
+				{
+					Name: to.StringPtr("Gateway-VMSS-redhat"),
+					VirtualMachineScaleSetProperties: &mgmtcompute.VirtualMachineScaleSetProperties{
+						VirtualMachineProfile: &mgmtcompute.VirtualMachineScaleSetVMProfile{
+							NetworkProfile: &mgmtcompute.VirtualMachineScaleSetNetworkProfile{},
+						},
+					},
+				},
+			},
+			instances: []mgmtcompute.VirtualMachineScaleSetVM{
+				{VirtualMachineScaleSetVMProperties: &mgmtcompute.VirtualMachineScaleSetVMProperties{LatestModelApplied: to.BoolPtr(true)}},
+			},
+			wantCondition: ConditionTrue,
+		},
 	}
 	for _, tt := range tests {
 		tt := tt
@@ -192,18 +249,38 @@ func TestUpdateProbe(t *testing.T) {
 			mockVMSS.EXPECT().List(gomock.Any(), gomock.Any()).AnyTimes().Return(tt.listReturn, tt.listErr)
 			mockVMSS.EXPECT().CreateOrUpdateAndWait(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().Return(tt.createOrUpdateErr)
 
+			mockVMSSVM := mock_compute.NewMockVirtualMachineScaleSetVMsClient(controller)
+			mockVMSSVM.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().Return(tt.instances, nil)
+
 			logger := logrus.Logger{}
 			logger.Out = io.Discard
 			c := cleaner{
-				log:  logrus.NewEntry(&logger),
-				vmss: mockVMSS,
+				log:    logrus.NewEntry(&logger),
+				vmss:   mockVMSS,
+				vmssvm: mockVMSSVM,
 			}
 			ctx := context.Background()
 			rg := "someid"
 			retry := c.UpdateVMSSProbes(ctx, rg)
-			if retry != tt.expected {
+			if (retry == nil) != tt.expected {
 				t.Error(retry)
 			}
+
+			if tt.wantCondition != "" {
+				found := false
+				for _, cond := range c.Conditions() {
+					if cond.Type != ConditionScaleSetModelUpdated {
+						continue
+					}
+					found = true
+					if cond.Status != tt.wantCondition {
+						t.Errorf("got condition status %s, want %s", cond.Status, tt.wantCondition)
+					}
+				}
+				if !found {
+					t.Error("ConditionScaleSetModelUpdated was never set")
+				}
+			}
 		})
 	}
 }
@@ -0,0 +1,112 @@
+package vmsscleaner
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"sync"
+	"time"
+)
+
+// ConditionType is the type of a condition reported by a cleaner, modeled
+// on the conditions used by cluster-api-provider-azure.
+type ConditionType string
+
+const (
+	// ConditionScaleSetDesiredReplicas reflects whether the VMSS being
+	// cleaned up is currently scaling toward its desired replica count.
+	ConditionScaleSetDesiredReplicas ConditionType = "ScaleSetDesiredReplicas"
+	// ConditionScaleSetModelUpdated reflects whether the running VMSS model
+	// matches the desired one.
+	ConditionScaleSetModelUpdated ConditionType = "ScaleSetModelUpdated"
+	// ConditionScaleSetDeleting reflects whether the cleaner has engaged to
+	// remove a failed VMSS.
+	ConditionScaleSetDeleting ConditionType = "ScaleSetDeleting"
+)
+
+// ConditionStatus is the status of a condition, following the
+// metav1.ConditionStatus convention.
+type ConditionStatus string
+
+const (
+	ConditionTrue    ConditionStatus = "True"
+	ConditionFalse   ConditionStatus = "False"
+	ConditionUnknown ConditionStatus = "Unknown"
+)
+
+const (
+	// ReasonScaleSetScalingUp is set on ConditionScaleSetDesiredReplicas
+	// while waiting for a new VMSS to reach its desired capacity.
+	ReasonScaleSetScalingUp = "ScaleSetScalingUp"
+	// ReasonScaleSetScalingDown is set on ConditionScaleSetDesiredReplicas
+	// while a failed VMSS is being drained ahead of deletion.
+	ReasonScaleSetScalingDown = "ScaleSetScalingDown"
+	// ReasonScaleSetModelOutOfDate is set on ConditionScaleSetModelUpdated
+	// when one or more instances have not yet reported LatestModelApplied
+	// for the scale set's current model.
+	ReasonScaleSetModelOutOfDate = "ScaleSetModelOutOfDate"
+	// ReasonScaleSetProvisionFailed is set on ConditionScaleSetDeleting when
+	// the cleaner engaged to remove a VMSS that failed to provision.
+	ReasonScaleSetProvisionFailed = "ScaleSetProvisionFailed"
+)
+
+// Condition is a single observation of the state of a VMSS cleanup,
+// suitable for surfacing to callers (e.g. the RP admin API or the
+// AdminUpdate state machine) that have no other visibility into progress.
+type Condition struct {
+	Type               ConditionType
+	Status             ConditionStatus
+	Reason             string
+	Message            string
+	LastTransitionTime time.Time
+}
+
+// ConditionsGetter is implemented by a Cleaner that tracks the progress of
+// its cleanup operations as a set of Conditions.
+type ConditionsGetter interface {
+	Conditions() []Condition
+}
+
+// Conditions returns a snapshot of the conditions recorded by the most
+// recent calls to RemoveFailedNewScaleset and UpdateVMSSProbes.
+func (c *cleaner) Conditions() []Condition {
+	c.conditionsMu.Lock()
+	defer c.conditionsMu.Unlock()
+
+	conditions := make([]Condition, len(c.conditions))
+	copy(conditions, c.conditions)
+	return conditions
+}
+
+// setCondition adds or updates the condition of the given type, bumping
+// LastTransitionTime only when the status actually changes.
+func (c *cleaner) setCondition(conditionType ConditionType, status ConditionStatus, reason, message string) {
+	c.conditionsMu.Lock()
+	defer c.conditionsMu.Unlock()
+
+	for i, existing := range c.conditions {
+		if existing.Type != conditionType {
+			continue
+		}
+
+		c.conditions[i].Reason = reason
+		c.conditions[i].Message = message
+		if existing.Status != status {
+			c.conditions[i].Status = status
+			c.conditions[i].LastTransitionTime = now()
+		}
+		return
+	}
+
+	c.conditions = append(c.conditions, Condition{
+		Type:               conditionType,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		LastTransitionTime: now(),
+	})
+}
+
+// now is a seam over time.Now so tests can observe LastTransitionTime
+// deterministically if needed.
+var now = time.Now
@@ -0,0 +1,77 @@
+package vmsscleaner
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	mgmtcompute "github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-01/compute"
+	"github.com/golang/mock/gomock"
+	"github.com/sirupsen/logrus"
+
+	mock_compute "github.com/Azure/ARO-RP/pkg/util/mocks/azureclient/mgmt/compute"
+)
+
+func TestConditions(t *testing.T) {
+	ctx := context.Background()
+	rg := "testRG"
+	vmssToDelete := "newVMSS"
+
+	logger := logrus.Logger{}
+	logger.Out = io.Discard
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockVMSS := mock_compute.NewMockVirtualMachineScaleSetsClient(controller)
+	mockVMSS.EXPECT().List(ctx, rg).Return(
+		[]mgmtcompute.VirtualMachineScaleSet{},
+		nil,
+	)
+
+	c := &cleaner{
+		log:  logrus.NewEntry(&logger),
+		vmss: mockVMSS,
+	}
+
+	c.RemoveFailedNewScaleset(ctx, rg, vmssToDelete)
+
+	conditions := c.Conditions()
+	if len(conditions) != 1 {
+		t.Fatalf("got %d conditions, want 1", len(conditions))
+	}
+
+	if conditions[0].Type != ConditionScaleSetDesiredReplicas {
+		t.Error(conditions[0].Type)
+	}
+	if conditions[0].Status != ConditionUnknown {
+		t.Error(conditions[0].Status)
+	}
+	if conditions[0].Reason != ReasonScaleSetScalingUp {
+		t.Error(conditions[0].Reason)
+	}
+	if conditions[0].LastTransitionTime.IsZero() {
+		t.Error("expected non-zero LastTransitionTime")
+	}
+}
+
+func TestSetConditionDoesNotBumpTransitionTimeWhenStatusUnchanged(t *testing.T) {
+	c := &cleaner{log: logrus.NewEntry(logrus.StandardLogger())}
+
+	c.setCondition(ConditionScaleSetDeleting, ConditionTrue, ReasonScaleSetProvisionFailed, "first")
+	first := c.Conditions()[0].LastTransitionTime
+
+	c.setCondition(ConditionScaleSetDeleting, ConditionTrue, ReasonScaleSetProvisionFailed, "second")
+	second := c.Conditions()[0].LastTransitionTime
+
+	if !first.Equal(second) {
+		t.Errorf("LastTransitionTime changed from %v to %v despite no status change", first, second)
+	}
+
+	if c.Conditions()[0].Message != "second" {
+		t.Error("expected message to be updated even when status is unchanged")
+	}
+}
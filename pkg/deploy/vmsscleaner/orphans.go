@@ -0,0 +1,141 @@
+package vmsscleaner
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"strings"
+
+	mgmtcompute "github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-01/compute"
+	mgmtnetwork "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2020-08-01/network"
+)
+
+// namedLikeScaleset reports whether name carries the naming prefix of the
+// deleted VMSS. This is only ever used as a secondary filter, after
+// confirming via the resource's own ManagedBy/attachment state that nothing
+// is still using it — it is not sufficient on its own, since a live
+// resource can coincidentally share the prefix (e.g. "newvmss-v2-nic-0"
+// alongside a deleted "newvmss").
+func namedLikeScaleset(name, vmssNamePrefix string) bool {
+	return strings.HasPrefix(strings.ToLower(name), strings.ToLower(vmssNamePrefix))
+}
+
+// orphanedInterface reports whether nic is detached from every VM and
+// carries the deleted scaleset's naming prefix. A NIC still attached to a
+// VM (VirtualMachine != nil) is never orphaned, no matter its name.
+func orphanedInterface(nic mgmtnetwork.Interface, vmssNamePrefix string) bool {
+	if nic.Name == nil {
+		return false
+	}
+
+	if nic.InterfacePropertiesFormat != nil && nic.VirtualMachine != nil {
+		return false
+	}
+
+	return namedLikeScaleset(*nic.Name, vmssNamePrefix)
+}
+
+// orphanedDisk reports whether disk is detached from every VM
+// (ManagedBy == nil) and carries the deleted scaleset's naming prefix.
+func orphanedDisk(disk mgmtcompute.Disk, vmssNamePrefix string) bool {
+	if disk.Name == nil {
+		return false
+	}
+
+	if disk.ManagedBy != nil {
+		return false
+	}
+
+	return namedLikeScaleset(*disk.Name, vmssNamePrefix)
+}
+
+// orphanedPublicIPAddress reports whether pip is detached from every NIC
+// (IPConfiguration == nil) and carries the deleted scaleset's naming
+// prefix.
+func orphanedPublicIPAddress(pip mgmtnetwork.PublicIPAddress, vmssNamePrefix string) bool {
+	if pip.Name == nil {
+		return false
+	}
+
+	if pip.PublicIPAddressPropertiesFormat != nil && pip.IPConfiguration != nil {
+		return false
+	}
+
+	return namedLikeScaleset(*pip.Name, vmssNamePrefix)
+}
+
+// CleanOrphanedResources deletes NICs, disks and public IPs in
+// resourceGroupName left behind by a deleted VMSS matching vmssNamePrefix.
+// Only resources that are actually detached from any VM are considered —
+// matching the deleted scaleset's naming prefix is a secondary filter on
+// top of that, never the sole criterion, since a live resource can
+// coincidentally share the prefix. When dryRun is true nothing is deleted;
+// matching resources are only logged. It returns nil on success, or a
+// *RetryError describing the first failure encountered.
+func (c *cleaner) CleanOrphanedResources(ctx context.Context, resourceGroupName, vmssNamePrefix string, dryRun bool) *RetryError {
+	nics, err := c.interfaces.List(ctx, resourceGroupName)
+	if err != nil {
+		return classifyError(err)
+	}
+
+	for _, nic := range nics {
+		if !orphanedInterface(nic, vmssNamePrefix) {
+			continue
+		}
+
+		if dryRun {
+			c.log.Printf("dry-run: would delete orphaned NIC %s", *nic.Name)
+			continue
+		}
+
+		c.log.Printf("deleting orphaned NIC %s", *nic.Name)
+		if err := c.interfaces.DeleteAndWait(ctx, resourceGroupName, *nic.Name); err != nil {
+			return classifyError(err)
+		}
+	}
+
+	disks, err := c.disks.ListByResourceGroup(ctx, resourceGroupName)
+	if err != nil {
+		return classifyError(err)
+	}
+
+	for _, disk := range disks {
+		if !orphanedDisk(disk, vmssNamePrefix) {
+			continue
+		}
+
+		if dryRun {
+			c.log.Printf("dry-run: would delete orphaned disk %s", *disk.Name)
+			continue
+		}
+
+		c.log.Printf("deleting orphaned disk %s", *disk.Name)
+		if err := c.disks.DeleteAndWait(ctx, resourceGroupName, *disk.Name); err != nil {
+			return classifyError(err)
+		}
+	}
+
+	publicIPAddresses, err := c.publicIPAddresses.List(ctx, resourceGroupName)
+	if err != nil {
+		return classifyError(err)
+	}
+
+	for _, pip := range publicIPAddresses {
+		if !orphanedPublicIPAddress(pip, vmssNamePrefix) {
+			continue
+		}
+
+		if dryRun {
+			c.log.Printf("dry-run: would delete orphaned public IP %s", *pip.Name)
+			continue
+		}
+
+		c.log.Printf("deleting orphaned public IP %s", *pip.Name)
+		if err := c.publicIPAddresses.DeleteAndWait(ctx, resourceGroupName, *pip.Name); err != nil {
+			return classifyError(err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,164 @@
+package vmsscleaner
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	mgmtcompute "github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-01/compute"
+	mgmtnetwork "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2020-08-01/network"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/golang/mock/gomock"
+	"github.com/sirupsen/logrus"
+
+	mock_compute "github.com/Azure/ARO-RP/pkg/util/mocks/azureclient/mgmt/compute"
+	mock_network "github.com/Azure/ARO-RP/pkg/util/mocks/azureclient/mgmt/network"
+)
+
+func TestCleanOrphanedResources(t *testing.T) {
+	ctx := context.Background()
+	rg := "testRG"
+	vmssNamePrefix := "newVMSS"
+
+	logger := logrus.Logger{}
+	logger.Out = io.Discard
+
+	for _, tt := range []struct {
+		name    string
+		dryRun  bool
+		mocks   func(*mock_network.MockInterfacesClient, *mock_compute.MockDisksClient, *mock_network.MockPublicIPAddressesClient)
+		wantErr bool
+	}{
+		{
+			name: "orphaned resources deleted",
+			mocks: func(nics *mock_network.MockInterfacesClient, disks *mock_compute.MockDisksClient, pips *mock_network.MockPublicIPAddressesClient) {
+				nics.EXPECT().List(ctx, rg).Return([]mgmtnetwork.Interface{
+					{Name: to.StringPtr("newVMSS-nic-0")},
+					{Name: to.StringPtr("unrelated-nic")},
+				}, nil)
+				nics.EXPECT().DeleteAndWait(ctx, rg, "newVMSS-nic-0").Return(nil)
+
+				disks.EXPECT().ListByResourceGroup(ctx, rg).Return([]mgmtcompute.Disk{
+					{Name: to.StringPtr("newVMSS-disk-0")},
+				}, nil)
+				disks.EXPECT().DeleteAndWait(ctx, rg, "newVMSS-disk-0").Return(nil)
+
+				pips.EXPECT().List(ctx, rg).Return([]mgmtnetwork.PublicIPAddress{
+					{Name: to.StringPtr("newVMSS-pip-0")},
+				}, nil)
+				pips.EXPECT().DeleteAndWait(ctx, rg, "newVMSS-pip-0").Return(nil)
+			},
+		},
+		{
+			name:   "dry run only logs, deletes nothing",
+			dryRun: true,
+			mocks: func(nics *mock_network.MockInterfacesClient, disks *mock_compute.MockDisksClient, pips *mock_network.MockPublicIPAddressesClient) {
+				nics.EXPECT().List(ctx, rg).Return([]mgmtnetwork.Interface{
+					{Name: to.StringPtr("newVMSS-nic-0")},
+				}, nil)
+
+				disks.EXPECT().ListByResourceGroup(ctx, rg).Return([]mgmtcompute.Disk{
+					{Name: to.StringPtr("newVMSS-disk-0")},
+				}, nil)
+
+				pips.EXPECT().List(ctx, rg).Return([]mgmtnetwork.PublicIPAddress{
+					{Name: to.StringPtr("newVMSS-pip-0")},
+				}, nil)
+			},
+		},
+		{
+			name: "NIC list fails, error propagated",
+			mocks: func(nics *mock_network.MockInterfacesClient, disks *mock_compute.MockDisksClient, pips *mock_network.MockPublicIPAddressesClient) {
+				nics.EXPECT().List(ctx, rg).Return(nil, errors.New("fake error"))
+			},
+			wantErr: true,
+		},
+		{
+			name: "NIC still attached to a VM is never deleted, even with a matching name",
+			mocks: func(nics *mock_network.MockInterfacesClient, disks *mock_compute.MockDisksClient, pips *mock_network.MockPublicIPAddressesClient) {
+				nics.EXPECT().List(ctx, rg).Return([]mgmtnetwork.Interface{
+					{
+						Name: to.StringPtr("newVMSS-v2-nic-0"),
+						InterfacePropertiesFormat: &mgmtnetwork.InterfacePropertiesFormat{
+							VirtualMachine: &mgmtnetwork.SubResource{ID: to.StringPtr("/subscriptions/x/resourceGroups/testRG/providers/Microsoft.Compute/virtualMachineScaleSets/newVMSS-v2/virtualMachines/0")},
+						},
+					},
+				}, nil)
+
+				disks.EXPECT().ListByResourceGroup(ctx, rg).Return(nil, nil)
+				pips.EXPECT().List(ctx, rg).Return(nil, nil)
+			},
+		},
+		{
+			name: "disk still managed by a VM is never deleted, even with a matching name",
+			mocks: func(nics *mock_network.MockInterfacesClient, disks *mock_compute.MockDisksClient, pips *mock_network.MockPublicIPAddressesClient) {
+				nics.EXPECT().List(ctx, rg).Return(nil, nil)
+
+				disks.EXPECT().ListByResourceGroup(ctx, rg).Return([]mgmtcompute.Disk{
+					{
+						Name:      to.StringPtr("newVMSS-disk-0"),
+						ManagedBy: to.StringPtr("/subscriptions/x/resourceGroups/testRG/providers/Microsoft.Compute/virtualMachineScaleSets/newVMSS/virtualMachines/0"),
+					},
+				}, nil)
+
+				pips.EXPECT().List(ctx, rg).Return(nil, nil)
+			},
+		},
+		{
+			name: "public IP still assigned to a NIC is never deleted, even with a matching name",
+			mocks: func(nics *mock_network.MockInterfacesClient, disks *mock_compute.MockDisksClient, pips *mock_network.MockPublicIPAddressesClient) {
+				nics.EXPECT().List(ctx, rg).Return(nil, nil)
+				disks.EXPECT().ListByResourceGroup(ctx, rg).Return(nil, nil)
+
+				pips.EXPECT().List(ctx, rg).Return([]mgmtnetwork.PublicIPAddress{
+					{
+						Name: to.StringPtr("newVMSS-pip-0"),
+						PublicIPAddressPropertiesFormat: &mgmtnetwork.PublicIPAddressPropertiesFormat{
+							IPConfiguration: &mgmtnetwork.IPConfiguration{ID: to.StringPtr("/subscriptions/x/.../ipConfigurations/ipconfig1")},
+						},
+					},
+				}, nil)
+			},
+		},
+		{
+			name: "detached disk with matching name is deleted, detached disk without matching name is left alone",
+			mocks: func(nics *mock_network.MockInterfacesClient, disks *mock_compute.MockDisksClient, pips *mock_network.MockPublicIPAddressesClient) {
+				nics.EXPECT().List(ctx, rg).Return(nil, nil)
+
+				disks.EXPECT().ListByResourceGroup(ctx, rg).Return([]mgmtcompute.Disk{
+					{Name: to.StringPtr("newVMSS-disk-0")},
+					{Name: to.StringPtr("unrelated-disk")},
+				}, nil)
+				disks.EXPECT().DeleteAndWait(ctx, rg, "newVMSS-disk-0").Return(nil)
+
+				pips.EXPECT().List(ctx, rg).Return(nil, nil)
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			mockNICs := mock_network.NewMockInterfacesClient(controller)
+			mockDisks := mock_compute.NewMockDisksClient(controller)
+			mockPIPs := mock_network.NewMockPublicIPAddressesClient(controller)
+			tt.mocks(mockNICs, mockDisks, mockPIPs)
+
+			c := cleaner{
+				log:               logrus.NewEntry(&logger),
+				interfaces:        mockNICs,
+				disks:             mockDisks,
+				publicIPAddresses: mockPIPs,
+			}
+
+			err := c.CleanOrphanedResources(ctx, rg, vmssNamePrefix, tt.dryRun)
+			if (err != nil) != tt.wantErr {
+				t.Error(err)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package vmsscleaner
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Azure/go-autorest/autorest"
+)
+
+// detailedErrorInfo is the subset of autorest.DetailedError that
+// classifyError cares about.
+type detailedErrorInfo struct {
+	statusCode int
+	response   *http.Response
+}
+
+// detailedError unwraps an autorest.DetailedError, if err is one.
+func detailedError(err error) (detailedErrorInfo, bool) {
+	de, ok := err.(autorest.DetailedError)
+	if !ok {
+		return detailedErrorInfo{}, false
+	}
+
+	statusCode, _ := de.StatusCode.(int)
+	return detailedErrorInfo{statusCode: statusCode, response: de.Response}, true
+}
+
+// RetryError describes the outcome of an operation that a caller may need
+// to retry, modeled on the retry.Error type used by cloud-provider-azure.
+// Unlike a plain bool it preserves why an operation failed, so callers can
+// build reliable retry loops instead of polling blindly.
+type RetryError struct {
+	// Retriable is true if the caller should retry the operation.
+	Retriable bool
+	// RetryAfter is the earliest time at which the operation should be
+	// retried. Zero if there is no specific guidance.
+	RetryAfter time.Time
+	// HTTPStatusCode is the HTTP status code returned by Azure, if any.
+	HTTPStatusCode int
+	// RawError is the underlying error that triggered this RetryError, if
+	// any. nil for synthetic "not ready yet" signals.
+	RawError error
+}
+
+// Error implements the error interface so *RetryError can be used anywhere
+// an error is expected.
+func (e *RetryError) Error() string {
+	if e == nil || e.RawError == nil {
+		return ""
+	}
+	return e.RawError.Error()
+}
+
+const (
+	// defaultRetryAfter is used for 429/503 responses that don't carry a
+	// Retry-After header.
+	defaultRetryAfter = 5 * time.Second
+	// maxRetryAfter caps the backoff applied to a generic 5xx response.
+	maxRetryAfter = 2 * time.Minute
+)
+
+// classifyError converts err, as returned by the compute SDK, into a
+// *RetryError. It returns nil if err is nil. autorest.DetailedError status
+// codes are used to decide retriability: 429/503 are retriable and honour
+// the response's Retry-After header, 404 is treated as non-retriable since
+// there is nothing left to act on, other 5xx responses are retriable with a
+// capped backoff, and anything else is treated as a terminal failure.
+func classifyError(err error) *RetryError {
+	if err == nil {
+		return nil
+	}
+
+	detailed, ok := detailedError(err)
+	if !ok {
+		return &RetryError{RawError: err}
+	}
+
+	switch detailed.statusCode {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		return &RetryError{
+			Retriable:      true,
+			RetryAfter:     retryAfter(detailed.response),
+			HTTPStatusCode: detailed.statusCode,
+			RawError:       err,
+		}
+	case http.StatusNotFound:
+		return &RetryError{
+			Retriable:      false,
+			HTTPStatusCode: detailed.statusCode,
+			RawError:       err,
+		}
+	}
+
+	if detailed.statusCode >= http.StatusInternalServerError {
+		return &RetryError{
+			Retriable:      true,
+			RetryAfter:     time.Now().Add(maxRetryAfter),
+			HTTPStatusCode: detailed.statusCode,
+			RawError:       err,
+		}
+	}
+
+	return &RetryError{
+		HTTPStatusCode: detailed.statusCode,
+		RawError:       err,
+	}
+}
+
+// retryAfter computes the backoff from the response's Retry-After header,
+// falling back to defaultRetryAfter if the header is absent or malformed.
+func retryAfter(resp *http.Response) time.Time {
+	if resp != nil {
+		if ra := resp.Header.Get("Retry-After"); ra != "" {
+			if seconds, err := strconv.Atoi(ra); err == nil {
+				return time.Now().Add(time.Duration(seconds) * time.Second)
+			}
+		}
+	}
+
+	return time.Now().Add(defaultRetryAfter)
+}
+
+// retriable is a convenience constructor for a *RetryError with no
+// underlying Azure error, used when a cleanup step simply isn't ready yet
+// (e.g. the target scaleset hasn't shown up in a List response).
+func retriable() *RetryError {
+	return &RetryError{Retriable: true}
+}
@@ -0,0 +1,101 @@
+package vmsscleaner
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/go-autorest/autorest"
+)
+
+func TestClassifyError(t *testing.T) {
+	for _, tt := range []struct {
+		name          string
+		err           error
+		wantNil       bool
+		wantRetriable bool
+		wantStatus    int
+	}{
+		{
+			name:    "nil error",
+			err:     nil,
+			wantNil: true,
+		},
+		{
+			name:          "plain error, not retriable",
+			err:           errors.New("fake error"),
+			wantRetriable: false,
+		},
+		{
+			name: "429 too many requests, retriable",
+			err: autorest.DetailedError{
+				Original:   errors.New("throttled"),
+				StatusCode: http.StatusTooManyRequests,
+				Response:   &http.Response{Header: http.Header{}},
+			},
+			wantRetriable: true,
+			wantStatus:    http.StatusTooManyRequests,
+		},
+		{
+			name: "503 service unavailable, retriable",
+			err: autorest.DetailedError{
+				Original:   errors.New("unavailable"),
+				StatusCode: http.StatusServiceUnavailable,
+				Response:   &http.Response{Header: http.Header{}},
+			},
+			wantRetriable: true,
+			wantStatus:    http.StatusServiceUnavailable,
+		},
+		{
+			name: "404 not found, non-retriable",
+			err: autorest.DetailedError{
+				Original:   errors.New("not found"),
+				StatusCode: http.StatusNotFound,
+			},
+			wantRetriable: false,
+			wantStatus:    http.StatusNotFound,
+		},
+		{
+			name: "500 internal server error, retriable with capped backoff",
+			err: autorest.DetailedError{
+				Original:   errors.New("internal error"),
+				StatusCode: http.StatusInternalServerError,
+			},
+			wantRetriable: true,
+			wantStatus:    http.StatusInternalServerError,
+		},
+		{
+			name: "400 bad request, non-retriable",
+			err: autorest.DetailedError{
+				Original:   errors.New("bad request"),
+				StatusCode: http.StatusBadRequest,
+			},
+			wantRetriable: false,
+			wantStatus:    http.StatusBadRequest,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got := classifyError(tt.err)
+
+			if tt.wantNil {
+				if got != nil {
+					t.Error(got)
+				}
+				return
+			}
+
+			if got.Retriable != tt.wantRetriable {
+				t.Errorf("got Retriable %v, want %v", got.Retriable, tt.wantRetriable)
+			}
+			if got.HTTPStatusCode != tt.wantStatus {
+				t.Errorf("got HTTPStatusCode %d, want %d", got.HTTPStatusCode, tt.wantStatus)
+			}
+			if got.Retriable && got.RetryAfter.IsZero() {
+				t.Error("expected non-zero RetryAfter for retriable error")
+			}
+		})
+	}
+}
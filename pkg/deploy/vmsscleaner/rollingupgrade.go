@@ -0,0 +1,221 @@
+package vmsscleaner
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	mgmtcompute "github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-01/compute"
+	"github.com/Azure/go-autorest/autorest/to"
+)
+
+// RollingUpgradeOptions controls the pace and failure tolerance of a
+// RollingUpgradeScaleset run.
+type RollingUpgradeOptions struct {
+	// BatchSize is the number of instances upgraded concurrently per batch.
+	// Defaults to 1 if zero.
+	BatchSize int
+	// MaxUnavailable caps how many instances in a single batch may still be
+	// reporting not-ready once the batch's upgrade operation has completed.
+	// Zero means every instance in the batch must be ready.
+	MaxUnavailable int
+	// MaxConsecutiveFailures aborts the rollout once this many instances in
+	// a row fail to move onto the latest model. Defaults to 1 if zero.
+	MaxConsecutiveFailures int
+	// Reimage additionally resets each instance's OS disk to the scale
+	// set's image after it has been moved onto the latest model. Use this
+	// to recover from OS-level drift; it is not the upgrade mechanism
+	// itself, which is UpdateInstances.
+	Reimage bool
+}
+
+// defaulted returns a copy of o with zero-valued fields replaced by their
+// defaults.
+func (o RollingUpgradeOptions) defaulted() RollingUpgradeOptions {
+	if o.BatchSize <= 0 {
+		o.BatchSize = 1
+	}
+	if o.MaxConsecutiveFailures <= 0 {
+		o.MaxConsecutiveFailures = 1
+	}
+	return o
+}
+
+// readinessPollInterval and readinessPollAttempts bound how long
+// waitUntilReady waits for an instance's power state to catch up with a
+// completed upgrade operation, before giving up on it. Variables, not
+// constants, so tests can shrink them.
+var (
+	readinessPollInterval = 5 * time.Second
+	readinessPollAttempts = 6
+)
+
+// instanceReady reports whether the VM's PowerState status indicates it has
+// come back up after an upgrade.
+func instanceReady(vm mgmtcompute.VirtualMachineScaleSetVM) bool {
+	if vm.InstanceView == nil || vm.InstanceView.Statuses == nil {
+		return false
+	}
+
+	for _, status := range *vm.InstanceView.Statuses {
+		if status.Code != nil && *status.Code == "PowerState/running" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// RollingUpgradeScaleset progressively moves every instance of vmssName in
+// resourceGroupName onto the scale set's latest model, in batches, honouring
+// opts.MaxUnavailable within a batch and aborting once
+// opts.MaxConsecutiveFailures instances in a row fail to upgrade.
+func (c *cleaner) RollingUpgradeScaleset(ctx context.Context, resourceGroupName, vmssName string, opts RollingUpgradeOptions) error {
+	opts = opts.defaulted()
+
+	instances, err := c.vmssvm.List(ctx, resourceGroupName, vmssName, "", "", "")
+	if err != nil {
+		return err
+	}
+
+	var instanceIDs []string
+	for _, instance := range instances {
+		if instance.InstanceID != nil {
+			instanceIDs = append(instanceIDs, *instance.InstanceID)
+		}
+	}
+
+	consecutiveFailures := 0
+
+	for i := 0; i < len(instanceIDs); i += opts.BatchSize {
+		end := i + opts.BatchSize
+		if end > len(instanceIDs) {
+			end = len(instanceIDs)
+		}
+		batch := instanceIDs[i:end]
+
+		if err := c.protectFromScaleIn(ctx, resourceGroupName, vmssName, batch, true); err != nil {
+			return err
+		}
+
+		c.log.Printf("upgrading batch of %d instance(s) in scaleset %s to the latest model", len(batch), vmssName)
+		err := c.vmss.UpdateInstancesAndWait(ctx, resourceGroupName, vmssName, mgmtcompute.VirtualMachineScaleSetVMInstanceRequiredIDs{
+			InstanceIds: &batch,
+		})
+		if err == nil && opts.Reimage {
+			err = c.ReimageInstances(ctx, resourceGroupName, vmssName, batch)
+		}
+
+		if err != nil {
+			consecutiveFailures += len(batch)
+			c.log.Error(err)
+
+			// Unprotect even on failure: otherwise a batch that never
+			// reaches the success path below is left with
+			// ProtectFromScaleIn=true forever, and nothing ever scales it
+			// back in.
+			if unprotectErr := c.protectFromScaleIn(ctx, resourceGroupName, vmssName, batch, false); unprotectErr != nil {
+				c.log.Error(unprotectErr)
+			}
+
+			if consecutiveFailures >= opts.MaxConsecutiveFailures {
+				return fmt.Errorf("aborting rolling upgrade of %s after %d consecutive instance failures", vmssName, consecutiveFailures)
+			}
+			continue
+		}
+
+		consecutiveFailures = 0
+
+		notReady, err := c.waitUntilReady(ctx, resourceGroupName, vmssName, batch)
+		if err != nil {
+			return err
+		}
+
+		if err := c.protectFromScaleIn(ctx, resourceGroupName, vmssName, batch, false); err != nil {
+			return err
+		}
+
+		if notReady > opts.MaxUnavailable {
+			return fmt.Errorf("aborting rolling upgrade of %s: %d instance(s) in the last batch still unavailable, exceeds MaxUnavailable of %d", vmssName, notReady, opts.MaxUnavailable)
+		}
+	}
+
+	return nil
+}
+
+// waitUntilReady polls instanceIDs' power state until every one reports
+// running or the poll budget is exhausted, returning how many are still not
+// ready. A completed upgrade operation doesn't guarantee the instance view
+// has caught up yet, so a single immediate check would misreport a healthy
+// batch as failed.
+func (c *cleaner) waitUntilReady(ctx context.Context, resourceGroupName, vmssName string, instanceIDs []string) (int, error) {
+	want := make(map[string]bool, len(instanceIDs))
+	for _, id := range instanceIDs {
+		want[id] = true
+	}
+
+	var notReady int
+	for attempt := 0; attempt < readinessPollAttempts; attempt++ {
+		instances, err := c.vmssvm.List(ctx, resourceGroupName, vmssName, "", "", "instanceView")
+		if err != nil {
+			return 0, err
+		}
+
+		notReady = 0
+		for _, instance := range instances {
+			if instance.InstanceID == nil || !want[*instance.InstanceID] {
+				continue
+			}
+			if !instanceReady(instance) {
+				notReady++
+			}
+		}
+
+		if notReady == 0 || attempt == readinessPollAttempts-1 {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return notReady, ctx.Err()
+		case <-time.After(readinessPollInterval):
+		}
+	}
+
+	return notReady, nil
+}
+
+// protectFromScaleIn toggles ProtectFromScaleIn on each instance, so that a
+// concurrent scale-in event can't reclaim an instance mid-upgrade.
+func (c *cleaner) protectFromScaleIn(ctx context.Context, resourceGroupName, vmssName string, instanceIDs []string, protect bool) error {
+	for _, instanceID := range instanceIDs {
+		err := c.vmssvm.UpdateAndWait(ctx, resourceGroupName, vmssName, instanceID, mgmtcompute.VirtualMachineScaleSetVM{
+			VirtualMachineScaleSetVMProperties: &mgmtcompute.VirtualMachineScaleSetVMProperties{
+				ProtectionPolicy: &mgmtcompute.VirtualMachineScaleSetVMProtectionPolicy{
+					ProtectFromScaleIn: to.BoolPtr(protect),
+				},
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to set ProtectFromScaleIn=%t on instance %s of scaleset %s: %w", protect, instanceID, vmssName, err)
+		}
+	}
+
+	return nil
+}
+
+// ReimageInstances reimages the given instanceIDs of vmssName in
+// resourceGroupName, one at a time, returning the first error encountered.
+func (c *cleaner) ReimageInstances(ctx context.Context, resourceGroupName, vmssName string, instanceIDs []string) error {
+	for _, instanceID := range instanceIDs {
+		err := c.vmssvm.ReimageAndWait(ctx, resourceGroupName, vmssName, instanceID, &mgmtcompute.VirtualMachineScaleSetVMReimageParameters{})
+		if err != nil {
+			return fmt.Errorf("failed to reimage instance %s of scaleset %s: %w", instanceID, vmssName, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,299 @@
+package vmsscleaner
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	mgmtcompute "github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-01/compute"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/golang/mock/gomock"
+	"github.com/sirupsen/logrus"
+
+	mock_compute "github.com/Azure/ARO-RP/pkg/util/mocks/azureclient/mgmt/compute"
+)
+
+func runningInstance(id string) mgmtcompute.VirtualMachineScaleSetVM {
+	return mgmtcompute.VirtualMachineScaleSetVM{
+		InstanceID: to.StringPtr(id),
+		InstanceView: &mgmtcompute.VirtualMachineScaleSetVMInstanceView{
+			Statuses: &[]mgmtcompute.InstanceViewStatus{
+				{Code: to.StringPtr("PowerState/running")},
+			},
+		},
+	}
+}
+
+func rebootingInstance(id string) mgmtcompute.VirtualMachineScaleSetVM {
+	return mgmtcompute.VirtualMachineScaleSetVM{
+		InstanceID:   to.StringPtr(id),
+		InstanceView: &mgmtcompute.VirtualMachineScaleSetVMInstanceView{},
+	}
+}
+
+func protectInstance(instanceID string, protect bool) mgmtcompute.VirtualMachineScaleSetVM {
+	return mgmtcompute.VirtualMachineScaleSetVM{
+		VirtualMachineScaleSetVMProperties: &mgmtcompute.VirtualMachineScaleSetVMProperties{
+			ProtectionPolicy: &mgmtcompute.VirtualMachineScaleSetVMProtectionPolicy{
+				ProtectFromScaleIn: to.BoolPtr(protect),
+			},
+		},
+	}
+}
+
+func TestInstanceReady(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		vm   mgmtcompute.VirtualMachineScaleSetVM
+		want bool
+	}{
+		{
+			name: "no instance view yet",
+			vm:   mgmtcompute.VirtualMachineScaleSetVM{},
+			want: false,
+		},
+		{
+			name: "instance view with no statuses populated yet",
+			vm:   rebootingInstance("0"),
+			want: false,
+		},
+		{
+			name: "running",
+			vm:   runningInstance("0"),
+			want: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := instanceReady(tt.vm); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReimageInstances(t *testing.T) {
+	ctx := context.Background()
+	rg := "testRG"
+	vmssName := "gateway-vmss-redhat"
+
+	for _, tt := range []struct {
+		name        string
+		instanceIDs []string
+		mocks       func(*mock_compute.MockVirtualMachineScaleSetVMsClient)
+		wantErr     string
+	}{
+		{
+			name:        "all instances reimaged successfully",
+			instanceIDs: []string{"0", "1"},
+			mocks: func(vmssvm *mock_compute.MockVirtualMachineScaleSetVMsClient) {
+				vmssvm.EXPECT().ReimageAndWait(ctx, rg, vmssName, "0", gomock.Any()).Return(nil)
+				vmssvm.EXPECT().ReimageAndWait(ctx, rg, vmssName, "1", gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name:        "reimage fails, error propagated",
+			instanceIDs: []string{"0"},
+			mocks: func(vmssvm *mock_compute.MockVirtualMachineScaleSetVMsClient) {
+				vmssvm.EXPECT().ReimageAndWait(ctx, rg, vmssName, "0", gomock.Any()).Return(errors.New("fake error"))
+			},
+			wantErr: "failed to reimage instance 0 of scaleset gateway-vmss-redhat: fake error",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			mockVMSSVM := mock_compute.NewMockVirtualMachineScaleSetVMsClient(controller)
+			tt.mocks(mockVMSSVM)
+
+			c := cleaner{
+				log:    logrus.NewEntry(logrus.StandardLogger()),
+				vmssvm: mockVMSSVM,
+			}
+
+			err := c.ReimageInstances(ctx, rg, vmssName, tt.instanceIDs)
+			if err == nil || tt.wantErr == "" {
+				if err != nil || tt.wantErr != "" {
+					t.Error(err)
+				}
+				return
+			}
+			if err.Error() != tt.wantErr {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestRollingUpgradeScaleset(t *testing.T) {
+	ctx := context.Background()
+	rg := "testRG"
+	vmssName := "gateway-vmss-redhat"
+
+	logger := logrus.Logger{}
+	logger.Out = io.Discard
+
+	// Keep the readiness poll from actually sleeping real wall-clock time,
+	// while still exercising its retry loop.
+	originalInterval, originalAttempts := readinessPollInterval, readinessPollAttempts
+	readinessPollInterval = time.Millisecond
+	readinessPollAttempts = 3
+	defer func() {
+		readinessPollInterval = originalInterval
+		readinessPollAttempts = originalAttempts
+	}()
+
+	for _, tt := range []struct {
+		name    string
+		opts    RollingUpgradeOptions
+		mocks   func(*mock_compute.MockVirtualMachineScaleSetVMsClient, *mock_compute.MockVirtualMachineScaleSetsClient)
+		wantErr string
+	}{
+		{
+			name: "single instance, batch size 1, succeeds",
+			opts: RollingUpgradeOptions{BatchSize: 1, MaxUnavailable: 1, MaxConsecutiveFailures: 1},
+			mocks: func(vmssvm *mock_compute.MockVirtualMachineScaleSetVMsClient, vmss *mock_compute.MockVirtualMachineScaleSetsClient) {
+				vmssvm.EXPECT().List(ctx, rg, vmssName, "", "", "").Return(
+					[]mgmtcompute.VirtualMachineScaleSetVM{runningInstance("0")}, nil,
+				)
+				vmssvm.EXPECT().UpdateAndWait(ctx, rg, vmssName, "0", protectInstance("0", true)).Return(nil)
+				vmss.EXPECT().UpdateInstancesAndWait(ctx, rg, vmssName, mgmtcompute.VirtualMachineScaleSetVMInstanceRequiredIDs{
+					InstanceIds: &[]string{"0"},
+				}).Return(nil)
+				vmssvm.EXPECT().List(ctx, rg, vmssName, "", "", "instanceView").Return(
+					[]mgmtcompute.VirtualMachineScaleSetVM{runningInstance("0")}, nil,
+				)
+				vmssvm.EXPECT().UpdateAndWait(ctx, rg, vmssName, "0", protectInstance("0", false)).Return(nil)
+			},
+		},
+		{
+			name: "list fails, error propagated",
+			opts: RollingUpgradeOptions{},
+			mocks: func(vmssvm *mock_compute.MockVirtualMachineScaleSetVMsClient, vmss *mock_compute.MockVirtualMachineScaleSetsClient) {
+				vmssvm.EXPECT().List(ctx, rg, vmssName, "", "", "").Return(nil, errors.New("fake error"))
+			},
+			wantErr: "fake error",
+		},
+		{
+			name: "upgrade fails immediately, aborts after single consecutive failure",
+			opts: RollingUpgradeOptions{BatchSize: 1, MaxConsecutiveFailures: 1},
+			mocks: func(vmssvm *mock_compute.MockVirtualMachineScaleSetVMsClient, vmss *mock_compute.MockVirtualMachineScaleSetsClient) {
+				vmssvm.EXPECT().List(ctx, rg, vmssName, "", "", "").Return(
+					[]mgmtcompute.VirtualMachineScaleSetVM{runningInstance("0")}, nil,
+				)
+				vmssvm.EXPECT().UpdateAndWait(ctx, rg, vmssName, "0", protectInstance("0", true)).Return(nil)
+				vmss.EXPECT().UpdateInstancesAndWait(ctx, rg, vmssName, mgmtcompute.VirtualMachineScaleSetVMInstanceRequiredIDs{
+					InstanceIds: &[]string{"0"},
+				}).Return(errors.New("fake error"))
+				vmssvm.EXPECT().UpdateAndWait(ctx, rg, vmssName, "0", protectInstance("0", false)).Return(nil)
+			},
+			wantErr: "aborting rolling upgrade of gateway-vmss-redhat after 1 consecutive instance failures",
+		},
+		{
+			name: "two batches: a not-yet-ready instance after the first batch gets a grace period instead of aborting the rollout",
+			opts: RollingUpgradeOptions{BatchSize: 1, MaxUnavailable: 0, MaxConsecutiveFailures: 1},
+			mocks: func(vmssvm *mock_compute.MockVirtualMachineScaleSetVMsClient, vmss *mock_compute.MockVirtualMachineScaleSetsClient) {
+				vmssvm.EXPECT().List(ctx, rg, vmssName, "", "", "").Return(
+					[]mgmtcompute.VirtualMachineScaleSetVM{runningInstance("0"), runningInstance("1")}, nil,
+				)
+
+				// Batch 1: instance 0 reboots slowly, only catching up to
+				// "running" on the second readiness poll.
+				vmssvm.EXPECT().UpdateAndWait(ctx, rg, vmssName, "0", protectInstance("0", true)).Return(nil)
+				vmss.EXPECT().UpdateInstancesAndWait(ctx, rg, vmssName, mgmtcompute.VirtualMachineScaleSetVMInstanceRequiredIDs{
+					InstanceIds: &[]string{"0"},
+				}).Return(nil)
+				vmssvm.EXPECT().List(ctx, rg, vmssName, "", "", "instanceView").Return(
+					[]mgmtcompute.VirtualMachineScaleSetVM{rebootingInstance("0")}, nil,
+				)
+				vmssvm.EXPECT().List(ctx, rg, vmssName, "", "", "instanceView").Return(
+					[]mgmtcompute.VirtualMachineScaleSetVM{runningInstance("0")}, nil,
+				)
+				vmssvm.EXPECT().UpdateAndWait(ctx, rg, vmssName, "0", protectInstance("0", false)).Return(nil)
+
+				// Batch 2: instance 1 upgrades and comes back up immediately.
+				vmssvm.EXPECT().UpdateAndWait(ctx, rg, vmssName, "1", protectInstance("1", true)).Return(nil)
+				vmss.EXPECT().UpdateInstancesAndWait(ctx, rg, vmssName, mgmtcompute.VirtualMachineScaleSetVMInstanceRequiredIDs{
+					InstanceIds: &[]string{"1"},
+				}).Return(nil)
+				vmssvm.EXPECT().List(ctx, rg, vmssName, "", "", "instanceView").Return(
+					[]mgmtcompute.VirtualMachineScaleSetVM{runningInstance("1")}, nil,
+				)
+				vmssvm.EXPECT().UpdateAndWait(ctx, rg, vmssName, "1", protectInstance("1", false)).Return(nil)
+			},
+		},
+		{
+			name: "instance still not ready once the poll budget is exhausted aborts the rollout",
+			opts: RollingUpgradeOptions{BatchSize: 1, MaxUnavailable: 0, MaxConsecutiveFailures: 1},
+			mocks: func(vmssvm *mock_compute.MockVirtualMachineScaleSetVMsClient, vmss *mock_compute.MockVirtualMachineScaleSetsClient) {
+				vmssvm.EXPECT().List(ctx, rg, vmssName, "", "", "").Return(
+					[]mgmtcompute.VirtualMachineScaleSetVM{runningInstance("0")}, nil,
+				)
+				vmssvm.EXPECT().UpdateAndWait(ctx, rg, vmssName, "0", protectInstance("0", true)).Return(nil)
+				vmss.EXPECT().UpdateInstancesAndWait(ctx, rg, vmssName, mgmtcompute.VirtualMachineScaleSetVMInstanceRequiredIDs{
+					InstanceIds: &[]string{"0"},
+				}).Return(nil)
+				vmssvm.EXPECT().List(ctx, rg, vmssName, "", "", "instanceView").Return(
+					[]mgmtcompute.VirtualMachineScaleSetVM{rebootingInstance("0")}, nil,
+				).Times(readinessPollAttempts)
+				vmssvm.EXPECT().UpdateAndWait(ctx, rg, vmssName, "0", protectInstance("0", false)).Return(nil)
+			},
+			wantErr: "aborting rolling upgrade of gateway-vmss-redhat: 1 instance(s) in the last batch still unavailable, exceeds MaxUnavailable of 0",
+		},
+		{
+			name: "a failed batch that doesn't abort the rollout is still unprotected, not left scale-in-protected forever",
+			opts: RollingUpgradeOptions{BatchSize: 1, MaxConsecutiveFailures: 2},
+			mocks: func(vmssvm *mock_compute.MockVirtualMachineScaleSetVMsClient, vmss *mock_compute.MockVirtualMachineScaleSetsClient) {
+				vmssvm.EXPECT().List(ctx, rg, vmssName, "", "", "").Return(
+					[]mgmtcompute.VirtualMachineScaleSetVM{runningInstance("0"), runningInstance("1")}, nil,
+				)
+
+				vmssvm.EXPECT().UpdateAndWait(ctx, rg, vmssName, "0", protectInstance("0", true)).Return(nil)
+				vmss.EXPECT().UpdateInstancesAndWait(ctx, rg, vmssName, mgmtcompute.VirtualMachineScaleSetVMInstanceRequiredIDs{
+					InstanceIds: &[]string{"0"},
+				}).Return(errors.New("fake error"))
+				vmssvm.EXPECT().UpdateAndWait(ctx, rg, vmssName, "0", protectInstance("0", false)).Return(nil)
+
+				vmssvm.EXPECT().UpdateAndWait(ctx, rg, vmssName, "1", protectInstance("1", true)).Return(nil)
+				vmss.EXPECT().UpdateInstancesAndWait(ctx, rg, vmssName, mgmtcompute.VirtualMachineScaleSetVMInstanceRequiredIDs{
+					InstanceIds: &[]string{"1"},
+				}).Return(nil)
+				vmssvm.EXPECT().List(ctx, rg, vmssName, "", "", "instanceView").Return(
+					[]mgmtcompute.VirtualMachineScaleSetVM{runningInstance("1")}, nil,
+				)
+				vmssvm.EXPECT().UpdateAndWait(ctx, rg, vmssName, "1", protectInstance("1", false)).Return(nil)
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			mockVMSSVM := mock_compute.NewMockVirtualMachineScaleSetVMsClient(controller)
+			mockVMSS := mock_compute.NewMockVirtualMachineScaleSetsClient(controller)
+			tt.mocks(mockVMSSVM, mockVMSS)
+
+			c := cleaner{
+				log:    logrus.NewEntry(&logger),
+				vmss:   mockVMSS,
+				vmssvm: mockVMSSVM,
+			}
+
+			err := c.RollingUpgradeScaleset(ctx, rg, vmssName, tt.opts)
+			if err == nil || tt.wantErr == "" {
+				if err != nil || tt.wantErr != "" {
+					t.Error(err)
+				}
+				return
+			}
+			if err.Error() != tt.wantErr {
+				t.Error(err)
+			}
+		})
+	}
+}
@@ -32,6 +32,10 @@ type ResponseMessageEnvelope struct {
 
 type ResponseMessageCollectionEnvelope struct {
 	Value []ResponseMessageEnvelope `json:"value,omitempty"`
+
+	// ContinuationToken resumes a ListDetectors call at the next page, and
+	// is empty once the final page has been returned.
+	ContinuationToken string `json:"-"`
 }
 
 // ListDetectors obtains the list of detectors for a service from AppLens.
@@ -60,7 +64,14 @@ func (c *Client) ListDetectors(
 		return nil, err
 	}
 
-	return newResponseMessageCollectionEnvelope(bodyJson, o.ResourceID, o.Location)
+	envelope, err := newResponseMessageCollectionEnvelope(bodyJson, o.ResourceID, o.Location)
+	if err != nil {
+		return nil, err
+	}
+
+	envelope.ContinuationToken = azResponse.Header.Get("x-ms-continuation")
+
+	return envelope, nil
 }
 
 // GetDetector obtains detector information from AppLens.
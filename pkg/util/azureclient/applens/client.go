@@ -0,0 +1,48 @@
+package applens
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+)
+
+// ClientOptions configures the retry and throttling behaviour of a Client.
+type ClientOptions struct {
+	Retry      RetryPolicy
+	Throttling ThrottlingPolicy
+}
+
+// NewClient returns a new Client that talks to the AppLens endpoint,
+// authenticating every request with cred.
+func NewClient(endpoint string, cred azcore.TokenCredential, options *ClientOptions) (*Client, error) {
+	if options == nil {
+		options = &ClientOptions{}
+	}
+
+	authPolicy := runtime.NewBearerTokenPolicy(cred, []string{endpoint + "/.default"}, nil)
+
+	// MaxRetries: -1 disables azcore's own built-in retry policy, which
+	// runtime.NewPipeline would otherwise insert ahead of PerRetry. Left
+	// enabled, it retries on the same 429/503/504 statuses as our
+	// RetryPolicy, so every one of our attempts would itself be re-driven
+	// from scratch, multiplying total HTTP calls instead of bounding them
+	// at RetryPolicy.MaxRetries.
+	pipeline := runtime.NewPipeline(
+		"applens",
+		"v1.0.0",
+		runtime.PipelineOptions{
+			PerRetry: []policy.Policy{authPolicy, options.Retry, &options.Throttling},
+		},
+		&policy.ClientOptions{
+			Retry: policy.RetryOptions{MaxRetries: -1},
+		},
+	)
+
+	return &Client{
+		endpoint: endpoint,
+		pipeline: pipeline,
+	}, nil
+}
@@ -0,0 +1,27 @@
+package applens
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// AppLensError is returned when AppLens responds with a non-2xx/304 status
+// code.
+type AppLensError struct {
+	StatusCode int
+	Status     string
+}
+
+func (e *AppLensError) Error() string {
+	return fmt.Sprintf("applens: unexpected status code %d (%s)", e.StatusCode, e.Status)
+}
+
+func newAppLensError(response *http.Response) error {
+	return &AppLensError{
+		StatusCode: response.StatusCode,
+		Status:     response.Status,
+	}
+}
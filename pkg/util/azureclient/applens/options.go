@@ -0,0 +1,65 @@
+package applens
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"net/http"
+	"strconv"
+)
+
+// appLensRequestOptions is implemented by every *Options type so
+// createRequest can turn it into the headers AppLens expects.
+type appLensRequestOptions interface {
+	toHeader() http.Header
+}
+
+// GetDetectorOptions contains the options for the GetDetector operation.
+type GetDetectorOptions struct {
+	// ResourceID is the ARM resource ID of the cluster the detector is for.
+	ResourceID string
+	// DetectorID is the id of the detector to fetch.
+	DetectorID string
+	// Location is the Azure region of the cluster, used to route the
+	// request to the regional AppLens endpoint.
+	Location string
+}
+
+func (o *GetDetectorOptions) toHeader() http.Header {
+	header := http.Header{}
+	header.Set("x-ms-path-query", o.ResourceID+"/detectors/"+o.DetectorID)
+	header.Set("x-ms-geo-location", o.Location)
+	return header
+}
+
+// ListDetectorsOptions contains the options for the ListDetectors operation.
+type ListDetectorsOptions struct {
+	// ResourceID is the ARM resource ID of the cluster to list detectors
+	// for.
+	ResourceID string
+	// Location is the Azure region of the cluster, used to route the
+	// request to the regional AppLens endpoint.
+	Location string
+	// ContinuationToken resumes a previous ListDetectors call at the page
+	// following the one that returned it. Leave empty to start from the
+	// first page.
+	ContinuationToken string
+	// MaxItemCount caps the number of detectors returned in a single page.
+	// Zero leaves the page size up to the server.
+	MaxItemCount int32
+}
+
+func (o *ListDetectorsOptions) toHeader() http.Header {
+	header := http.Header{}
+	header.Set("x-ms-path-query", o.ResourceID+"/detectors")
+	header.Set("x-ms-geo-location", o.Location)
+
+	if o.ContinuationToken != "" {
+		header.Set("x-ms-continuation", o.ContinuationToken)
+	}
+	if o.MaxItemCount > 0 {
+		header.Set("x-ms-max-item-count", strconv.Itoa(int(o.MaxItemCount)))
+	}
+
+	return header
+}
@@ -0,0 +1,43 @@
+package applens
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+)
+
+// ListDetectorsPageResponse is a single page of ListDetectors results.
+type ListDetectorsPageResponse struct {
+	ResponseMessageCollectionEnvelope
+}
+
+// NewListDetectorsPager returns a pager over the detectors matching o,
+// fetching each page lazily as the caller advances it instead of buffering
+// every detector up front.
+func (c *Client) NewListDetectorsPager(o *ListDetectorsOptions) *runtime.Pager[ListDetectorsPageResponse] {
+	if o == nil {
+		o = &ListDetectorsOptions{}
+	}
+	options := *o
+
+	return runtime.NewPager(runtime.PagingHandler[ListDetectorsPageResponse]{
+		More: func(page ListDetectorsPageResponse) bool {
+			return page.ContinuationToken != ""
+		},
+		Fetcher: func(ctx context.Context, page *ListDetectorsPageResponse) (ListDetectorsPageResponse, error) {
+			if page != nil {
+				options.ContinuationToken = page.ContinuationToken
+			}
+
+			result, err := c.ListDetectors(ctx, &options)
+			if err != nil {
+				return ListDetectorsPageResponse{}, err
+			}
+
+			return ListDetectorsPageResponse{ResponseMessageCollectionEnvelope: *result}, nil
+		},
+	})
+}
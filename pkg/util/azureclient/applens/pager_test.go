@@ -0,0 +1,113 @@
+package applens
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+)
+
+func jsonResponse(statusCode int, body string, header http.Header) *http.Response {
+	if header == nil {
+		header = http.Header{}
+	}
+	header.Set("Content-Type", "application/json")
+
+	return &http.Response{
+		StatusCode: statusCode,
+		Status:     http.StatusText(statusCode),
+		Header:     header,
+		Body:       io.NopCloser(strings.NewReader(body)),
+	}
+}
+
+type scriptedTransporter struct {
+	responses []*http.Response
+	calls     int
+}
+
+func (t *scriptedTransporter) Do(req *http.Request) (*http.Response, error) {
+	resp := t.responses[t.calls]
+	t.calls++
+	return resp, nil
+}
+
+func TestNewListDetectorsPagerIteratesAllPages(t *testing.T) {
+	transport := &scriptedTransporter{
+		responses: []*http.Response{
+			jsonResponse(http.StatusOK, `[{"metadata":{"id":"detector-a"}}]`, http.Header{"X-Ms-Continuation": []string{"page-2"}}),
+			jsonResponse(http.StatusOK, `[{"metadata":{"id":"detector-b"}}]`, nil),
+		},
+	}
+
+	pipeline := runtime.NewPipeline("applens-test", "v1.0.0", runtime.PipelineOptions{}, &policy.ClientOptions{
+		Transport: transport,
+		Retry:     policy.RetryOptions{MaxRetries: -1},
+	})
+	c := &Client{endpoint: "https://example.com", pipeline: pipeline}
+
+	pager := c.NewListDetectorsPager(&ListDetectorsOptions{ResourceID: "/subscriptions/x", Location: "eastus"})
+
+	var names []string
+	for pager.More() {
+		page, err := pager.NextPage(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, detector := range page.Value {
+			names = append(names, detector.Name)
+		}
+	}
+
+	if transport.calls != 2 {
+		t.Errorf("got %d request(s), want 2", transport.calls)
+	}
+
+	want := []string{"detector-a", "detector-b"}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("got %v, want %v", names, want)
+	}
+}
+
+func TestNewListDetectorsPagerSinglePage(t *testing.T) {
+	transport := &scriptedTransporter{
+		responses: []*http.Response{
+			jsonResponse(http.StatusOK, `[{"metadata":{"id":"detector-a"}}]`, nil),
+		},
+	}
+
+	pipeline := runtime.NewPipeline("applens-test", "v1.0.0", runtime.PipelineOptions{}, &policy.ClientOptions{
+		Transport: transport,
+		Retry:     policy.RetryOptions{MaxRetries: -1},
+	})
+	c := &Client{endpoint: "https://example.com", pipeline: pipeline}
+
+	pager := c.NewListDetectorsPager(&ListDetectorsOptions{ResourceID: "/subscriptions/x", Location: "eastus"})
+
+	if !pager.More() {
+		t.Fatal("expected at least one page")
+	}
+
+	page, err := pager.NextPage(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Value) != 1 || page.Value[0].Name != "detector-a" {
+		t.Errorf("got %+v, want a single detector-a", page.Value)
+	}
+
+	if pager.More() {
+		t.Error("expected no further pages once the continuation token is empty")
+	}
+	if transport.calls != 1 {
+		t.Errorf("got %d request(s), want 1", transport.calls)
+	}
+}
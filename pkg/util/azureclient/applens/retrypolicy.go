@@ -0,0 +1,109 @@
+package applens
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+)
+
+// RetryPolicy retries requests that AppLens responds to with a throttling
+// (429) or transient server (503/504) status, honouring the Retry-After or
+// x-ms-retry-after-ms response headers when present and falling back to an
+// exponential backoff otherwise.
+type RetryPolicy struct {
+	// MaxRetries caps the number of retry attempts. Defaults to 3 if zero.
+	MaxRetries int
+	// BaseDelay is the delay before the first retry, doubled on each
+	// subsequent attempt. Defaults to 1 second if zero.
+	BaseDelay time.Duration
+}
+
+func (p RetryPolicy) maxRetries() int {
+	if p.MaxRetries > 0 {
+		return p.MaxRetries
+	}
+	return 3
+}
+
+func (p RetryPolicy) baseDelay() time.Duration {
+	if p.BaseDelay > 0 {
+		return p.BaseDelay
+	}
+	return time.Second
+}
+
+// Do implements policy.Policy.
+func (p RetryPolicy) Do(req *policy.Request) (*http.Response, error) {
+	var resp *http.Response
+	var err error
+
+	for attempt := 0; ; attempt++ {
+		resp, err = req.Next()
+		if err != nil || resp == nil || !isRetriableStatusCode(resp.StatusCode) || attempt == p.maxRetries() {
+			return resp, err
+		}
+
+		delay := p.retryDelay(resp, attempt)
+		drainAndClose(resp.Body)
+
+		select {
+		case <-req.Raw().Context().Done():
+			return resp, req.Raw().Context().Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+// drainAndClose reads body to completion and closes it, so the connection it
+// holds can be reused instead of leaked. It must be called on every
+// retriable response before issuing the next attempt, since req.Next()
+// otherwise overwrites resp without anyone having consumed its body.
+func drainAndClose(body io.ReadCloser) {
+	if body == nil {
+		return
+	}
+	_, _ = io.Copy(io.Discard, body)
+	body.Close()
+}
+
+func (p RetryPolicy) retryDelay(resp *http.Response, attempt int) time.Duration {
+	if delay, ok := retryAfterFromHeaders(resp); ok {
+		return delay
+	}
+
+	return p.baseDelay() * time.Duration(int64(1)<<uint(attempt))
+}
+
+func isRetriableStatusCode(statusCode int) bool {
+	switch statusCode {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// retryAfterFromHeaders reads the server's preferred retry delay, checking
+// x-ms-retry-after-ms before falling back to the standard Retry-After
+// header.
+func retryAfterFromHeaders(resp *http.Response) (time.Duration, bool) {
+	if ms := resp.Header.Get("x-ms-retry-after-ms"); ms != "" {
+		if millis, err := strconv.Atoi(ms); err == nil {
+			return time.Duration(millis) * time.Millisecond, true
+		}
+	}
+
+	if ra := resp.Header.Get("Retry-After"); ra != "" {
+		if seconds, err := strconv.Atoi(ra); err == nil {
+			return time.Duration(seconds) * time.Second, true
+		}
+	}
+
+	return 0, false
+}
@@ -0,0 +1,192 @@
+package applens
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+)
+
+func TestRetryAfterFromHeaders(t *testing.T) {
+	for _, tt := range []struct {
+		name      string
+		header    http.Header
+		wantDelay time.Duration
+		wantOK    bool
+	}{
+		{
+			name:      "x-ms-retry-after-ms takes precedence over Retry-After",
+			header:    http.Header{"X-Ms-Retry-After-Ms": []string{"250"}, "Retry-After": []string{"5"}},
+			wantDelay: 250 * time.Millisecond,
+			wantOK:    true,
+		},
+		{
+			name:      "falls back to Retry-After in seconds when x-ms-retry-after-ms is absent",
+			header:    http.Header{"Retry-After": []string{"2"}},
+			wantDelay: 2 * time.Second,
+			wantOK:    true,
+		},
+		{
+			name:   "neither header present",
+			header: http.Header{},
+			wantOK: false,
+		},
+		{
+			name:      "unparseable x-ms-retry-after-ms falls back to Retry-After",
+			header:    http.Header{"X-Ms-Retry-After-Ms": []string{"not-a-number"}, "Retry-After": []string{"1"}},
+			wantDelay: time.Second,
+			wantOK:    true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{Header: tt.header}
+
+			delay, ok := retryAfterFromHeaders(resp)
+			if ok != tt.wantOK || delay != tt.wantDelay {
+				t.Errorf("got (%v, %v), want (%v, %v)", delay, ok, tt.wantDelay, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestRetryPolicyDo(t *testing.T) {
+	for _, tt := range []struct {
+		name           string
+		maxRetries     int
+		statusCodes    []int
+		wantCalls      int32
+		wantStatusCode int
+	}{
+		{
+			name:           "recovers after two throttled responses",
+			maxRetries:     3,
+			statusCodes:    []int{http.StatusTooManyRequests, http.StatusServiceUnavailable, http.StatusOK},
+			wantCalls:      3,
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "gives up after MaxRetries and returns the last response",
+			maxRetries:     2,
+			statusCodes:    []int{http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusServiceUnavailable},
+			wantCalls:      3,
+			wantStatusCode: http.StatusServiceUnavailable,
+		},
+		{
+			name:           "non-retriable status code returned on the first attempt",
+			maxRetries:     3,
+			statusCodes:    []int{http.StatusNotFound},
+			wantCalls:      1,
+			wantStatusCode: http.StatusNotFound,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int32
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n := int(atomic.AddInt32(&calls, 1)) - 1
+				if n >= len(tt.statusCodes) {
+					n = len(tt.statusCodes) - 1
+				}
+				w.Header().Set("x-ms-retry-after-ms", "1")
+				w.WriteHeader(tt.statusCodes[n])
+				_, _ = w.Write([]byte("body"))
+			}))
+			defer server.Close()
+
+			pipeline := runtime.NewPipeline("applens-test", "v1.0.0", runtime.PipelineOptions{
+				PerRetry: []policy.Policy{RetryPolicy{MaxRetries: tt.maxRetries, BaseDelay: time.Millisecond}},
+			}, &policy.ClientOptions{
+				Transport: server.Client(),
+				Retry:     policy.RetryOptions{MaxRetries: -1},
+			})
+
+			req, err := runtime.NewRequest(context.Background(), http.MethodGet, server.URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			resp, err := pipeline.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.wantStatusCode {
+				t.Errorf("got status %d, want %d", resp.StatusCode, tt.wantStatusCode)
+			}
+			if got := atomic.LoadInt32(&calls); got != tt.wantCalls {
+				t.Errorf("got %d request(s), want %d", got, tt.wantCalls)
+			}
+		})
+	}
+}
+
+// trackingBody records whether Close was called, so tests can verify
+// RetryPolicy.Do drains and closes every response body it discards on
+// retry, instead of leaking the underlying connection.
+type trackingBody struct {
+	*strings.Reader
+	closed bool
+}
+
+func (b *trackingBody) Close() error {
+	b.closed = true
+	return nil
+}
+
+type fakeTransporter struct {
+	responses []*http.Response
+	calls     int
+}
+
+func (f *fakeTransporter) Do(req *http.Request) (*http.Response, error) {
+	resp := f.responses[f.calls]
+	f.calls++
+	return resp, nil
+}
+
+func TestRetryPolicyDoClosesDiscardedResponseBodies(t *testing.T) {
+	throttled := &trackingBody{Reader: strings.NewReader("throttled")}
+	ok := &trackingBody{Reader: strings.NewReader("ok")}
+
+	transport := &fakeTransporter{
+		responses: []*http.Response{
+			{StatusCode: http.StatusTooManyRequests, Header: http.Header{}, Body: throttled},
+			{StatusCode: http.StatusOK, Header: http.Header{}, Body: ok},
+		},
+	}
+
+	pipeline := runtime.NewPipeline("applens-test", "v1.0.0", runtime.PipelineOptions{
+		PerRetry: []policy.Policy{RetryPolicy{MaxRetries: 1, BaseDelay: time.Millisecond}},
+	}, &policy.ClientOptions{
+		Transport: transport,
+		Retry:     policy.RetryOptions{MaxRetries: -1},
+	})
+
+	req, err := runtime.NewRequest(context.Background(), http.MethodGet, "https://example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := pipeline.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if !throttled.closed {
+		t.Error("body of the discarded throttled response was never closed, leaking its connection")
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("got status %d, want 200", resp.StatusCode)
+	}
+}
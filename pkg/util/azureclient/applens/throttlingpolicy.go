@@ -0,0 +1,63 @@
+package applens
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+)
+
+// ThrottlingPolicy paces outgoing requests to stay under a client-side rate
+// limit, complementing RetryPolicy's reactive handling of 429 responses by
+// avoiding them in the first place.
+type ThrottlingPolicy struct {
+	// RequestsPerSecond caps the outgoing request rate. Defaults to 10 if
+	// zero.
+	RequestsPerSecond int
+
+	mu   sync.Mutex
+	last time.Time
+}
+
+func (p *ThrottlingPolicy) interval() time.Duration {
+	rps := p.RequestsPerSecond
+	if rps <= 0 {
+		rps = 10
+	}
+	return time.Second / time.Duration(rps)
+}
+
+// Do implements policy.Policy.
+func (p *ThrottlingPolicy) Do(req *policy.Request) (*http.Response, error) {
+	if wait := p.reserve(); wait > 0 {
+		select {
+		case <-req.Raw().Context().Done():
+			return nil, req.Raw().Context().Err()
+		case <-time.After(wait):
+		}
+	}
+
+	return req.Next()
+}
+
+// reserve claims the next send slot and returns how long the caller must
+// wait before using it.
+func (p *ThrottlingPolicy) reserve() time.Duration {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	earliest := p.last.Add(p.interval())
+
+	if now.After(earliest) {
+		p.last = now
+		return 0
+	}
+
+	p.last = earliest
+	return earliest.Sub(now)
+}
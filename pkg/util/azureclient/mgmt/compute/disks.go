@@ -0,0 +1,62 @@
+package compute
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+
+	mgmtcompute "github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-01/compute"
+	"github.com/Azure/go-autorest/autorest"
+
+	"github.com/Azure/ARO-RP/pkg/util/azureclient"
+)
+
+// DisksClient is a minimal interface for azure DisksClient
+type DisksClient interface {
+	DeleteAndWait(ctx context.Context, resourceGroupName string, diskName string) error
+	ListByResourceGroup(ctx context.Context, resourceGroupName string) (result []mgmtcompute.Disk, err error)
+}
+
+type disksClient struct {
+	mgmtcompute.DisksClient
+}
+
+var _ DisksClient = &disksClient{}
+
+// NewDisksClient creates a new DisksClient
+func NewDisksClient(environment *azureclient.AROEnvironment, subscriptionID string, authorizer autorest.Authorizer) DisksClient {
+	client := mgmtcompute.NewDisksClientWithBaseURI(environment.ResourceManagerEndpoint, subscriptionID)
+	client.Authorizer = authorizer
+
+	return &disksClient{
+		DisksClient: client,
+	}
+}
+
+func (c *disksClient) DeleteAndWait(ctx context.Context, resourceGroupName string, diskName string) error {
+	future, err := c.DisksClient.Delete(ctx, resourceGroupName, diskName)
+	if err != nil {
+		return err
+	}
+
+	return future.WaitForCompletionRef(ctx, c.Client)
+}
+
+func (c *disksClient) ListByResourceGroup(ctx context.Context, resourceGroupName string) (result []mgmtcompute.Disk, err error) {
+	page, err := c.DisksClient.ListByResourceGroup(ctx, resourceGroupName)
+	if err != nil {
+		return nil, err
+	}
+
+	for page.NotDone() {
+		result = append(result, page.Values()...)
+
+		err = page.NextWithContext(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,84 @@
+package compute
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+
+	mgmtcompute "github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-01/compute"
+	"github.com/Azure/go-autorest/autorest"
+
+	"github.com/Azure/ARO-RP/pkg/util/azureclient"
+)
+
+// VirtualMachineScaleSetsClient is a minimal interface for azure VirtualMachineScaleSetsClient
+type VirtualMachineScaleSetsClient interface {
+	CreateOrUpdateAndWait(ctx context.Context, resourceGroupName string, vmScaleSetName string, parameters mgmtcompute.VirtualMachineScaleSet) error
+	DeleteAndWait(ctx context.Context, resourceGroupName string, vmScaleSetName string) error
+	List(ctx context.Context, resourceGroupName string) (result []mgmtcompute.VirtualMachineScaleSet, err error)
+	UpdateInstancesAndWait(ctx context.Context, resourceGroupName string, vmScaleSetName string, vmInstanceIDs mgmtcompute.VirtualMachineScaleSetVMInstanceRequiredIDs) error
+}
+
+type virtualMachineScaleSetsClient struct {
+	mgmtcompute.VirtualMachineScaleSetsClient
+}
+
+var _ VirtualMachineScaleSetsClient = &virtualMachineScaleSetsClient{}
+
+// NewVirtualMachineScaleSetsClient creates a new VirtualMachineScaleSetsClient
+func NewVirtualMachineScaleSetsClient(environment *azureclient.AROEnvironment, subscriptionID string, authorizer autorest.Authorizer) VirtualMachineScaleSetsClient {
+	client := mgmtcompute.NewVirtualMachineScaleSetsClientWithBaseURI(environment.ResourceManagerEndpoint, subscriptionID)
+	client.Authorizer = authorizer
+
+	return &virtualMachineScaleSetsClient{
+		VirtualMachineScaleSetsClient: client,
+	}
+}
+
+func (c *virtualMachineScaleSetsClient) CreateOrUpdateAndWait(ctx context.Context, resourceGroupName string, vmScaleSetName string, parameters mgmtcompute.VirtualMachineScaleSet) error {
+	future, err := c.VirtualMachineScaleSetsClient.CreateOrUpdate(ctx, resourceGroupName, vmScaleSetName, parameters)
+	if err != nil {
+		return err
+	}
+
+	return future.WaitForCompletionRef(ctx, c.Client)
+}
+
+func (c *virtualMachineScaleSetsClient) DeleteAndWait(ctx context.Context, resourceGroupName string, vmScaleSetName string) error {
+	future, err := c.VirtualMachineScaleSetsClient.Delete(ctx, resourceGroupName, vmScaleSetName, nil)
+	if err != nil {
+		return err
+	}
+
+	return future.WaitForCompletionRef(ctx, c.Client)
+}
+
+func (c *virtualMachineScaleSetsClient) List(ctx context.Context, resourceGroupName string) (result []mgmtcompute.VirtualMachineScaleSet, err error) {
+	page, err := c.VirtualMachineScaleSetsClient.List(ctx, resourceGroupName)
+	if err != nil {
+		return nil, err
+	}
+
+	for page.NotDone() {
+		result = append(result, page.Values()...)
+
+		err = page.NextWithContext(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// UpdateInstancesAndWait triggers a manual upgrade of the given instances to
+// the scale set's latest model and waits for the operation to complete.
+func (c *virtualMachineScaleSetsClient) UpdateInstancesAndWait(ctx context.Context, resourceGroupName string, vmScaleSetName string, vmInstanceIDs mgmtcompute.VirtualMachineScaleSetVMInstanceRequiredIDs) error {
+	future, err := c.VirtualMachineScaleSetsClient.UpdateInstances(ctx, resourceGroupName, vmScaleSetName, vmInstanceIDs)
+	if err != nil {
+		return err
+	}
+
+	return future.WaitForCompletionRef(ctx, c.Client)
+}
@@ -0,0 +1,72 @@
+package compute
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+
+	mgmtcompute "github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-01/compute"
+	"github.com/Azure/go-autorest/autorest"
+
+	"github.com/Azure/ARO-RP/pkg/util/azureclient"
+)
+
+// VirtualMachineScaleSetVMsClient is a minimal interface for azure VirtualMachineScaleSetVMsClient
+type VirtualMachineScaleSetVMsClient interface {
+	List(ctx context.Context, resourceGroupName string, vmScaleSetName string, filter string, selectParameter string, expand string) (result []mgmtcompute.VirtualMachineScaleSetVM, err error)
+	ReimageAndWait(ctx context.Context, resourceGroupName string, vmScaleSetName string, instanceID string, parameters *mgmtcompute.VirtualMachineScaleSetVMReimageParameters) error
+	UpdateAndWait(ctx context.Context, resourceGroupName string, vmScaleSetName string, instanceID string, parameters mgmtcompute.VirtualMachineScaleSetVM) error
+}
+
+type virtualMachineScaleSetVMsClient struct {
+	mgmtcompute.VirtualMachineScaleSetVMsClient
+}
+
+var _ VirtualMachineScaleSetVMsClient = &virtualMachineScaleSetVMsClient{}
+
+// NewVirtualMachineScaleSetVMsClient creates a new VirtualMachineScaleSetVMsClient
+func NewVirtualMachineScaleSetVMsClient(environment *azureclient.AROEnvironment, subscriptionID string, authorizer autorest.Authorizer) VirtualMachineScaleSetVMsClient {
+	client := mgmtcompute.NewVirtualMachineScaleSetVMsClientWithBaseURI(environment.ResourceManagerEndpoint, subscriptionID)
+	client.Authorizer = authorizer
+
+	return &virtualMachineScaleSetVMsClient{
+		VirtualMachineScaleSetVMsClient: client,
+	}
+}
+
+func (c *virtualMachineScaleSetVMsClient) List(ctx context.Context, resourceGroupName string, vmScaleSetName string, filter string, selectParameter string, expand string) (result []mgmtcompute.VirtualMachineScaleSetVM, err error) {
+	page, err := c.VirtualMachineScaleSetVMsClient.List(ctx, resourceGroupName, vmScaleSetName, filter, selectParameter, expand)
+	if err != nil {
+		return nil, err
+	}
+
+	for page.NotDone() {
+		result = append(result, page.Values()...)
+
+		err = page.NextWithContext(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+func (c *virtualMachineScaleSetVMsClient) ReimageAndWait(ctx context.Context, resourceGroupName string, vmScaleSetName string, instanceID string, parameters *mgmtcompute.VirtualMachineScaleSetVMReimageParameters) error {
+	future, err := c.VirtualMachineScaleSetVMsClient.Reimage(ctx, resourceGroupName, vmScaleSetName, instanceID, parameters)
+	if err != nil {
+		return err
+	}
+
+	return future.WaitForCompletionRef(ctx, c.Client)
+}
+
+func (c *virtualMachineScaleSetVMsClient) UpdateAndWait(ctx context.Context, resourceGroupName string, vmScaleSetName string, instanceID string, parameters mgmtcompute.VirtualMachineScaleSetVM) error {
+	future, err := c.VirtualMachineScaleSetVMsClient.Update(ctx, resourceGroupName, vmScaleSetName, instanceID, parameters)
+	if err != nil {
+		return err
+	}
+
+	return future.WaitForCompletionRef(ctx, c.Client)
+}
@@ -0,0 +1,62 @@
+package network
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+
+	mgmtnetwork "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2020-08-01/network"
+	"github.com/Azure/go-autorest/autorest"
+
+	"github.com/Azure/ARO-RP/pkg/util/azureclient"
+)
+
+// InterfacesClient is a minimal interface for azure InterfacesClient
+type InterfacesClient interface {
+	DeleteAndWait(ctx context.Context, resourceGroupName string, networkInterfaceName string) error
+	List(ctx context.Context, resourceGroupName string) (result []mgmtnetwork.Interface, err error)
+}
+
+type interfacesClient struct {
+	mgmtnetwork.InterfacesClient
+}
+
+var _ InterfacesClient = &interfacesClient{}
+
+// NewInterfacesClient creates a new InterfacesClient
+func NewInterfacesClient(environment *azureclient.AROEnvironment, subscriptionID string, authorizer autorest.Authorizer) InterfacesClient {
+	client := mgmtnetwork.NewInterfacesClientWithBaseURI(environment.ResourceManagerEndpoint, subscriptionID)
+	client.Authorizer = authorizer
+
+	return &interfacesClient{
+		InterfacesClient: client,
+	}
+}
+
+func (c *interfacesClient) DeleteAndWait(ctx context.Context, resourceGroupName string, networkInterfaceName string) error {
+	future, err := c.InterfacesClient.Delete(ctx, resourceGroupName, networkInterfaceName)
+	if err != nil {
+		return err
+	}
+
+	return future.WaitForCompletionRef(ctx, c.Client)
+}
+
+func (c *interfacesClient) List(ctx context.Context, resourceGroupName string) (result []mgmtnetwork.Interface, err error) {
+	page, err := c.InterfacesClient.List(ctx, resourceGroupName)
+	if err != nil {
+		return nil, err
+	}
+
+	for page.NotDone() {
+		result = append(result, page.Values()...)
+
+		err = page.NextWithContext(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
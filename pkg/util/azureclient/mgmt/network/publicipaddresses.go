@@ -0,0 +1,62 @@
+package network
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+
+	mgmtnetwork "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2020-08-01/network"
+	"github.com/Azure/go-autorest/autorest"
+
+	"github.com/Azure/ARO-RP/pkg/util/azureclient"
+)
+
+// PublicIPAddressesClient is a minimal interface for azure PublicIPAddressesClient
+type PublicIPAddressesClient interface {
+	DeleteAndWait(ctx context.Context, resourceGroupName string, publicIPAddressName string) error
+	List(ctx context.Context, resourceGroupName string) (result []mgmtnetwork.PublicIPAddress, err error)
+}
+
+type publicIPAddressesClient struct {
+	mgmtnetwork.PublicIPAddressesClient
+}
+
+var _ PublicIPAddressesClient = &publicIPAddressesClient{}
+
+// NewPublicIPAddressesClient creates a new PublicIPAddressesClient
+func NewPublicIPAddressesClient(environment *azureclient.AROEnvironment, subscriptionID string, authorizer autorest.Authorizer) PublicIPAddressesClient {
+	client := mgmtnetwork.NewPublicIPAddressesClientWithBaseURI(environment.ResourceManagerEndpoint, subscriptionID)
+	client.Authorizer = authorizer
+
+	return &publicIPAddressesClient{
+		PublicIPAddressesClient: client,
+	}
+}
+
+func (c *publicIPAddressesClient) DeleteAndWait(ctx context.Context, resourceGroupName string, publicIPAddressName string) error {
+	future, err := c.PublicIPAddressesClient.Delete(ctx, resourceGroupName, publicIPAddressName)
+	if err != nil {
+		return err
+	}
+
+	return future.WaitForCompletionRef(ctx, c.Client)
+}
+
+func (c *publicIPAddressesClient) List(ctx context.Context, resourceGroupName string) (result []mgmtnetwork.PublicIPAddress, err error) {
+	page, err := c.PublicIPAddressesClient.List(ctx, resourceGroupName)
+	if err != nil {
+		return nil, err
+	}
+
+	for page.NotDone() {
+		result = append(result, page.Values()...)
+
+		err = page.NextWithContext(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}